@@ -2,34 +2,68 @@ package syncmap
 
 import (
 	"sync/atomic"
-	"unsafe"
 )
 
 // entry is a value in a Map. All uses of its fields must be atomic.
-type entry struct {
-	p unsafe.Pointer // *interface{}
+type entry[V any] struct {
+	p atomic.Pointer[V]
 }
 
-func newEntry(v interface{}) *entry {
-	return &entry{unsafe.Pointer(&v)}
+func newEntry[V any](v V) *entry[V] {
+	e := &entry[V]{}
+	e.p.Store(&v)
+	return e
 }
 
-func (e *entry) load() (interface{}, bool) {
+func (e *entry[V]) load() (v V, ok bool) {
 	if e == nil {
-		return nil, false
+		return v, false
 	}
-	p := atomic.LoadPointer(&e.p)
+	p := e.p.Load()
 	if p == nil {
 		// Nil means deleted.
-		return nil, false
+		return v, false
 	}
-	return *(*interface{})(p), true
+	return *p, true
 }
 
-func (e *entry) store(v interface{}) {
-	atomic.StorePointer(&e.p, unsafe.Pointer(&v))
+func (e *entry[V]) store(v V) {
+	e.p.Store(&v)
 }
 
-func (e *entry) delete() (old *interface{}) {
-	return (*interface{})(atomic.SwapPointer(&e.p, nil))
+func (e *entry[V]) delete() (old *V) {
+	return e.p.Swap(nil)
+}
+
+// swap stores v and returns the value it replaced. ok is false if the entry
+// had already been deleted.
+func (e *entry[V]) swap(v V) (previous V, ok bool) {
+	p := e.p.Swap(&v)
+	if p == nil {
+		return previous, false
+	}
+	return *p, true
+}
+
+// compareAndSwap stores new if the entry's current value is equal to old,
+// reporting whether it did so. It fails if the entry has been deleted. It
+// panics if old and the current value have the same uncomparable dynamic type.
+func (e *entry[V]) compareAndSwap(old, new V) bool {
+	p := e.p.Load()
+	if p == nil || any(*p) != any(old) {
+		return false
+	}
+	return e.p.CompareAndSwap(p, &new)
+}
+
+// compareAndDelete deletes the entry if its current value is equal to old,
+// reporting whether it did so. It fails if the entry has already been
+// deleted. It panics if old and the current value have the same uncomparable
+// dynamic type.
+func (e *entry[V]) compareAndDelete(old V) bool {
+	p := e.p.Load()
+	if p == nil || any(*p) != any(old) {
+		return false
+	}
+	return e.p.CompareAndSwap(p, nil)
 }
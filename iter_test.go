@@ -0,0 +1,84 @@
+package syncmap_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zephyrtronium/syncmap"
+)
+
+func TestAll(t *testing.T) {
+	var m syncmap.Map[string, string]
+	keys := strings.Fields("a b c d e f g h i j k l m n o p q r s t u v w x y z")
+	for _, k := range keys {
+		m.Store(k, k)
+	}
+	seen := make(map[string]bool, len(keys))
+	for k, v := range m.All() {
+		if seen[k] {
+			t.Errorf("saw key %v again", k)
+		}
+		if v != k {
+			t.Errorf("wrong value for key %v: got %v", k, v)
+		}
+		seen[k] = true
+	}
+	if len(seen) != len(keys) {
+		t.Errorf("wrong keys: want %v, got %v", keys, seen)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	var m syncmap.Map[string, string]
+	keys := strings.Fields("a b c d e f g h i j k l m n o p q r s t u v w x y z")
+	for _, k := range keys {
+		m.Store(k, k)
+	}
+	seen := make(map[string]bool, len(keys))
+	for k := range m.Keys() {
+		if seen[k] {
+			t.Errorf("saw key %v again", k)
+		}
+		seen[k] = true
+	}
+	if len(seen) != len(keys) {
+		t.Errorf("wrong keys: want %v, got %v", keys, seen)
+	}
+}
+
+func TestValues(t *testing.T) {
+	var m syncmap.Map[string, int]
+	keys := strings.Fields("a b c d e f g h i j k l m n o p q r s t u v w x y z")
+	for i, k := range keys {
+		m.Store(k, i)
+	}
+	sum := 0
+	n := 0
+	for v := range m.Values() {
+		sum += v
+		n++
+	}
+	if n != len(keys) {
+		t.Errorf("wrong number of values: want %d, got %d", len(keys), n)
+	}
+	want := len(keys) * (len(keys) - 1) / 2
+	if sum != want {
+		t.Errorf("wrong sum of values: want %d, got %d", want, sum)
+	}
+}
+
+func TestAllStop(t *testing.T) {
+	var m syncmap.Map[string, string]
+	keys := strings.Fields("a b c d e f g h i j k l m n o p q r s t u v w x y z")
+	for _, k := range keys {
+		m.Store(k, k)
+	}
+	n := 0
+	for range m.All() {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("wrong number of iters: want 1, got %d", n)
+	}
+}
@@ -0,0 +1,151 @@
+package syncmap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/maphash"
+	"math"
+	"reflect"
+	"runtime"
+)
+
+// ShardedMap is a concurrent map like Map, but splits its keys across
+// several independent Map shards. Map serializes all dirty-path writes and
+// misses behind a single mutex; spreading keys across shards turns that
+// contention into per-shard contention, which scales much better for many
+// goroutines writing to distinct keys concurrently.
+//
+// The zero value of ShardedMap is not ready for use; create one with
+// NewShardedMap.
+//
+// Sharding hashes k on every call. Strings and fixed-size kinds (the
+// integer, float, bool, and pointer kinds) hash directly from their bits
+// and are cheap. Keys of other kinds, such as structs or arrays, fall back
+// to hashing a formatted representation, which is considerably slower and
+// allocates; prefer one of the fast kinds as the key type when ShardedMap
+// is used for a write-heavy workload.
+type ShardedMap[K comparable, V any] struct {
+	shards []Map[K, V]
+	seed   maphash.Seed
+}
+
+// NewShardedMap creates a ShardedMap with the given number of shards. If
+// shards is not positive, it defaults to runtime.GOMAXPROCS(0) rounded up to
+// the next power of two.
+func NewShardedMap[K comparable, V any](shards int) *ShardedMap[K, V] {
+	if shards <= 0 {
+		shards = nextPow2(runtime.GOMAXPROCS(0))
+	}
+	return &ShardedMap[K, V]{
+		shards: make([]Map[K, V], shards),
+		seed:   maphash.MakeSeed(),
+	}
+}
+
+// nextPow2 returns the smallest power of two that is at least n.
+func nextPow2(n int) int {
+	k := 1
+	for k < n {
+		k <<= 1
+	}
+	return k
+}
+
+// shard returns the Map responsible for k.
+func (m *ShardedMap[K, V]) shard(k K) *Map[K, V] {
+	return &m.shards[m.hash(k)%uint64(len(m.shards))]
+}
+
+// hash computes the shard hash of k. See the ShardedMap doc comment for
+// which kinds of k are hashed directly from their bits rather than through
+// the formatted fallback.
+func (m *ShardedMap[K, V]) hash(k K) uint64 {
+	if s, ok := any(k).(string); ok {
+		return maphash.String(m.seed, s)
+	}
+
+	var buf [8]byte
+	rv := reflect.ValueOf(k)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		binary.LittleEndian.PutUint64(buf[:], uint64(rv.Int()))
+		return maphash.Bytes(m.seed, buf[:])
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		binary.LittleEndian.PutUint64(buf[:], rv.Uint())
+		return maphash.Bytes(m.seed, buf[:])
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f == 0 {
+			f = 0 // -0 and +0 are equal keys, so they must hash alike.
+		}
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(f))
+		return maphash.Bytes(m.seed, buf[:])
+	case reflect.Bool:
+		if rv.Bool() {
+			buf[0] = 1
+		}
+		return maphash.Bytes(m.seed, buf[:1])
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		binary.LittleEndian.PutUint64(buf[:], uint64(rv.Pointer()))
+		return maphash.Bytes(m.seed, buf[:])
+	case reflect.String:
+		return maphash.String(m.seed, rv.String())
+	default:
+		// Structs, arrays, interfaces, and other composite comparable
+		// types: fall back to a formatted representation. This is the
+		// slow path documented on ShardedMap.
+		var h maphash.Hash
+		h.SetSeed(m.seed)
+		fmt.Fprintf(&h, "%v", k)
+		return h.Sum64()
+	}
+}
+
+// Store sets the value at a key.
+func (m *ShardedMap[K, V]) Store(k K, v V) {
+	m.shard(k).Store(k, v)
+}
+
+// Load gets the value at a key. ok is false if the key was not in the map.
+func (m *ShardedMap[K, V]) Load(k K) (v V, ok bool) {
+	return m.shard(k).Load(k)
+}
+
+// LoadOrStore gets the value at a key if it exists or stores and returns v if
+// it does not. loaded is true if the value already existed.
+func (m *ShardedMap[K, V]) LoadOrStore(k K, v V) (r V, loaded bool) {
+	return m.shard(k).LoadOrStore(k, v)
+}
+
+// Delete deletes the value at a key.
+func (m *ShardedMap[K, V]) Delete(k K) {
+	m.shard(k).Delete(k)
+}
+
+// LoadAndDelete deletes the value at a key, returning its old value and
+// whether it existed.
+func (m *ShardedMap[K, V]) LoadAndDelete(k K) (v V, loaded bool) {
+	return m.shard(k).LoadAndDelete(k)
+}
+
+// Range calls f for each key and its corresponding value across all shards.
+// If f returns false, the iteration ceases. There is no single promotion
+// barrier across the whole ShardedMap: each shard is ranged independently
+// under its own promotion barrier, matching the semantics of Map.Range, so
+// concurrent writes to one shard are not ordered against the iteration of
+// any other.
+func (m *ShardedMap[K, V]) Range(f func(key K, value V) bool) {
+	for i := range m.shards {
+		done := false
+		m.shards[i].Range(func(k K, v V) bool {
+			if !f(k, v) {
+				done = true
+				return false
+			}
+			return true
+		})
+		if done {
+			return
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package syncmap_test
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/zephyrtronium/syncmap"
+)
+
+func TestShardedMapKey(t *testing.T) {
+	m := syncmap.NewShardedMap[string, int](0)
+	if _, ok := m.Load("key"); ok {
+		t.Fatal("key exists in new map")
+	}
+	var wg sync.WaitGroup
+	n := runtime.GOMAXPROCS(0)
+	wg.Add(n)
+	ch := make(chan bool)
+	f := func(v int) {
+		<-ch
+		for i := 0; i < 1e5; i++ {
+			m.Load("key")
+			m.Store("key", v)
+		}
+		wg.Done()
+	}
+	for i := 0; i < n; i++ {
+		go f(i)
+	}
+	close(ch)
+	wg.Wait()
+	// No correct result, except that the race detector shouldn't complain.
+}
+
+func TestShardedMapLoadOrStore(t *testing.T) {
+	m := syncmap.NewShardedMap[string, int](4)
+	n := runtime.GOMAXPROCS(0)
+	ch := make(chan bool)
+	errs := make(chan error, n)
+	f := func(v int) {
+		<-ch
+		for i := 0; i < 1e4; i++ {
+			m.LoadOrStore(fmt.Sprintf("key%d", i), v)
+		}
+		errs <- nil
+	}
+	for i := 0; i < n; i++ {
+		go f(i)
+	}
+	close(ch)
+	for i := 0; i < n; i++ {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+	for i := 0; i < 1e4; i++ {
+		if _, ok := m.Load(fmt.Sprintf("key%d", i)); !ok {
+			t.Errorf("missing key%d after LoadOrStore", i)
+		}
+	}
+}
+
+func TestShardedMapDelete(t *testing.T) {
+	m := syncmap.NewShardedMap[string, int](4)
+	m.Store("k", 0)
+	v, ok := m.LoadAndDelete("k")
+	if !ok || v != 0 {
+		t.Errorf("delete returned wrong value: want 0/true, got %v/%t", v, ok)
+	}
+	if _, ok := m.Load("k"); ok {
+		t.Errorf("deleted key k was loaded")
+	}
+	m.Store("k", 1)
+	m.Delete("k")
+	if _, ok := m.Load("k"); ok {
+		t.Errorf("deleted key k was loaded")
+	}
+}
+
+func TestShardedMapRange(t *testing.T) {
+	m := syncmap.NewShardedMap[string, string](4)
+	keys := strings.Fields("a b c d e f g h i j k l m n o p q r s t u v w x y z")
+	for _, k := range keys {
+		m.Store(k, k)
+	}
+	seen := make(map[string]bool, len(keys))
+	m.Range(func(key, value string) bool {
+		if seen[key] {
+			t.Errorf("saw key %v again", key)
+		}
+		seen[key] = true
+		return true
+	})
+	if len(seen) != len(keys) {
+		t.Errorf("wrong keys: want %v, got %v", keys, seen)
+	}
+}
+
+func TestShardedMapNonStringKey(t *testing.T) {
+	m := syncmap.NewShardedMap[int, int](4)
+	for i := 0; i < 100; i++ {
+		m.Store(i, i*i)
+	}
+	for i := 0; i < 100; i++ {
+		v, ok := m.Load(i)
+		if !ok || v != i*i {
+			t.Errorf("wrong value for key %d: want %d, got %v/%t", i, i*i, v, ok)
+		}
+	}
+}
+
+// TestShardedMapStructKey exercises the formatted-fallback hash path used
+// for key kinds that aren't handled directly, such as structs.
+func TestShardedMapStructKey(t *testing.T) {
+	type point struct{ x, y int }
+	m := syncmap.NewShardedMap[point, string](4)
+	pts := []point{{0, 0}, {1, 2}, {-1, 3}, {5, -5}}
+	for _, p := range pts {
+		m.Store(p, fmt.Sprintf("%v", p))
+	}
+	for _, p := range pts {
+		v, ok := m.Load(p)
+		if !ok || v != fmt.Sprintf("%v", p) {
+			t.Errorf("wrong value for key %v: got %v/%t", p, v, ok)
+		}
+	}
+}
@@ -2,23 +2,29 @@
 package syncmap
 
 import (
+	"iter"
 	"sync"
 	"sync/atomic"
 )
 
-// Map is a concurrent read-mostly map, much like sync.Map with string keys.
-type Map struct {
-	v atomic.Value // map[string]*entry
+// Map is a concurrent read-mostly map, much like sync.Map but with type
+// parameters in place of interface{} keys and values.
+type Map[K comparable, V any] struct {
+	v atomic.Value // map[K]*entry[V]
 
 	// mu must be held when using dirty or misses.
 	mu     sync.Mutex
-	dirty  map[string]*entry
+	dirty  map[K]*entry[V]
 	misses int
 }
 
+// StringMap is the original string-keyed, any-valued form of Map, kept as an
+// alias for code written before Map gained type parameters.
+type StringMap = Map[string, any]
+
 // Store sets the value at a key.
-func (m *Map) Store(k string, v interface{}) {
-	mv, _ := m.v.Load().(map[string]*entry)
+func (m *Map[K, V]) Store(k K, v V) {
+	mv, _ := m.v.Load().(map[K]*entry[V])
 	e := mv[k]
 	if e != nil {
 		e.store(v)
@@ -27,7 +33,7 @@ func (m *Map) Store(k string, v interface{}) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	// Reload e in case another goroutine set it while we were locking.
-	mv, _ = m.v.Load().(map[string]*entry)
+	mv, _ = m.v.Load().(map[K]*entry[V])
 	e = mv[k]
 	if e != nil {
 		e.store(v)
@@ -43,8 +49,8 @@ func (m *Map) Store(k string, v interface{}) {
 }
 
 // Load gets the value at a key. ok is false if the key was not in the map.
-func (m *Map) Load(k string) (v interface{}, ok bool) {
-	mv, _ := m.v.Load().(map[string]*entry)
+func (m *Map[K, V]) Load(k K) (v V, ok bool) {
+	mv, _ := m.v.Load().(map[K]*entry[V])
 	e, ok := mv[k]
 	if ok {
 		return e.load()
@@ -52,7 +58,7 @@ func (m *Map) Load(k string) (v interface{}, ok bool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	// Reload e in case another goroutine set it while we were locking.
-	mv, _ = m.v.Load().(map[string]*entry)
+	mv, _ = m.v.Load().(map[K]*entry[V])
 	e, ok = mv[k]
 	if !ok {
 		e, ok = m.dirty[k]
@@ -63,8 +69,8 @@ func (m *Map) Load(k string) (v interface{}, ok bool) {
 
 // LoadOrStore gets the value at a key if it exists or stores and returns v if
 // it does not. loaded is true if the value already existed.
-func (m *Map) LoadOrStore(k string, v interface{}) (r interface{}, loaded bool) {
-	mv, _ := m.v.Load().(map[string]*entry)
+func (m *Map[K, V]) LoadOrStore(k K, v V) (r V, loaded bool) {
+	mv, _ := m.v.Load().(map[K]*entry[V])
 	e, ok := mv[k]
 	if ok {
 		return e.load()
@@ -72,7 +78,7 @@ func (m *Map) LoadOrStore(k string, v interface{}) (r interface{}, loaded bool)
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	// Reload e in case another goroutine set it while we were locking.
-	mv, _ = m.v.Load().(map[string]*entry)
+	mv, _ = m.v.Load().(map[K]*entry[V])
 	e, ok = mv[k]
 	if ok {
 		return e.load()
@@ -88,31 +94,31 @@ func (m *Map) LoadOrStore(k string, v interface{}) (r interface{}, loaded bool)
 }
 
 // Delete deletes the value at a key.
-func (m *Map) Delete(k string) {
+func (m *Map[K, V]) Delete(k K) {
 	m.LoadAndDelete(k)
 }
 
 // LoadAndDelete deletes the value at a key, returning its old value and
 // whether it existed.
-func (m *Map) LoadAndDelete(k string) (interface{}, bool) {
-	mv, _ := m.v.Load().(map[string]*entry)
+func (m *Map[K, V]) LoadAndDelete(k K) (v V, loaded bool) {
+	mv, _ := m.v.Load().(map[K]*entry[V])
 	e := mv[k]
 	if e != nil {
 		if p := e.delete(); p != nil {
 			return *p, true
 		}
-		return nil, false
+		return v, false
 	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	// Reload e in case another goroutine set it while we were locking.
-	mv, _ = m.v.Load().(map[string]*entry)
+	mv, _ = m.v.Load().(map[K]*entry[V])
 	e = mv[k]
 	if e != nil {
 		if p := e.delete(); p != nil {
 			return *p, true
 		}
-		return nil, false
+		return v, false
 	}
 	e = m.dirty[k]
 	m.miss()
@@ -121,20 +127,92 @@ func (m *Map) LoadAndDelete(k string) (interface{}, bool) {
 			return *p, true
 		}
 	}
-	return nil, false
+	return v, false
 }
 
-// Range calls f for each key and its corresponding value in the map. If f
-// returns false, the iteration ceases. Note that Range is O(n) even if f
-// returns false after a constant number of calls.
-func (m *Map) Range(f func(key string, value interface{}) bool) {
+// Swap stores v at a key and returns the value it replaced. loaded is false
+// if the key did not already have a value.
+func (m *Map[K, V]) Swap(k K, v V) (previous V, loaded bool) {
+	mv, _ := m.v.Load().(map[K]*entry[V])
+	e, ok := mv[k]
+	if ok {
+		return e.swap(v)
+	}
 	m.mu.Lock()
-	// Force miss to promote.
-	m.misses = len(m.dirty) - 1
+	defer m.mu.Unlock()
+	// Reload e in case another goroutine set it while we were locking.
+	mv, _ = m.v.Load().(map[K]*entry[V])
+	e, ok = mv[k]
+	if ok {
+		return e.swap(v)
+	}
+	e, ok = m.dirty[k]
+	// Whether we load or store, this is a miss.
 	m.miss()
-	mv, _ := m.v.Load().(map[string]*entry)
-	m.mu.Unlock()
+	if ok {
+		return e.swap(v)
+	}
+	m.dirty[k] = newEntry(v)
+	return previous, false
+}
+
+// CompareAndSwap stores new at k if the value currently stored there is
+// equal to old, reporting whether it did so. old must be of a comparable
+// type: if old and the current value have the same dynamic type and it does
+// not support ==, CompareAndSwap panics.
+func (m *Map[K, V]) CompareAndSwap(k K, old, new V) bool {
+	mv, _ := m.v.Load().(map[K]*entry[V])
+	e, ok := mv[k]
+	if ok {
+		return e.compareAndSwap(old, new)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Reload e in case another goroutine set it while we were locking.
+	mv, _ = m.v.Load().(map[K]*entry[V])
+	e, ok = mv[k]
+	if ok {
+		return e.compareAndSwap(old, new)
+	}
+	e, ok = m.dirty[k]
+	m.miss()
+	if !ok {
+		return false
+	}
+	return e.compareAndSwap(old, new)
+}
+
+// CompareAndDelete deletes the entry at k if its value is equal to old,
+// reporting whether it did so. old must be of a comparable type: if old and
+// the current value have the same dynamic type and it does not support ==,
+// CompareAndDelete panics.
+func (m *Map[K, V]) CompareAndDelete(k K, old V) (deleted bool) {
+	mv, _ := m.v.Load().(map[K]*entry[V])
+	e, ok := mv[k]
+	if ok {
+		return e.compareAndDelete(old)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Reload e in case another goroutine set it while we were locking.
+	mv, _ = m.v.Load().(map[K]*entry[V])
+	e, ok = mv[k]
+	if ok {
+		return e.compareAndDelete(old)
+	}
+	e, ok = m.dirty[k]
+	m.miss()
+	if !ok {
+		return false
+	}
+	return e.compareAndDelete(old)
+}
 
+// Range calls f for each key and its corresponding value in the map. If f
+// returns false, the iteration ceases. Note that Range is O(n) even if f
+// returns false after a constant number of calls.
+func (m *Map[K, V]) Range(f func(key K, value V) bool) {
+	mv := m.promoted()
 	for k, v := range mv {
 		if r, ok := v.load(); ok {
 			if !f(k, r) {
@@ -144,18 +222,118 @@ func (m *Map) Range(f func(key string, value interface{}) bool) {
 	}
 }
 
+// All returns an iterator over every key and its corresponding value in the
+// map, for use with a range-over-func loop. It has the same semantics as
+// Range.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		mv := m.promoted()
+		for k, v := range mv {
+			if r, ok := v.load(); ok {
+				if !yield(k, r) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Keys returns an iterator over every key in the map, for use with a
+// range-over-func loop. It has the same semantics as Range.
+func (m *Map[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		mv := m.promoted()
+		for k, v := range mv {
+			if _, ok := v.load(); ok {
+				if !yield(k) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Values returns an iterator over every value in the map, for use with a
+// range-over-func loop. It has the same semantics as Range.
+func (m *Map[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		mv := m.promoted()
+		for _, v := range mv {
+			if r, ok := v.load(); ok {
+				if !yield(r) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Len returns an approximate count of the keys in the map. Because Map is
+// concurrent, the result can be stale as soon as it is returned; it is the
+// size of the currently-promoted read map plus any keys present only in the
+// dirty map.
+func (m *Map[K, V]) Len() int {
+	mv, _ := m.v.Load().(map[K]*entry[V])
+	m.mu.Lock()
+	n := len(mv)
+	for k := range m.dirty {
+		if _, ok := mv[k]; !ok {
+			n++
+		}
+	}
+	m.mu.Unlock()
+	return n
+}
+
+// Clone returns a copy of the map as a plain map. It promotes the dirty map
+// and then copies each live entry in turn without holding m.mu, so the result
+// is not an atomic snapshot: writes made concurrently with Clone may or may
+// not be reflected in it.
+func (m *Map[K, V]) Clone() map[K]V {
+	mv := m.promoted()
+	r := make(map[K]V, len(mv))
+	for k, e := range mv {
+		if v, ok := e.load(); ok {
+			r[k] = v
+		}
+	}
+	return r
+}
+
+// Clear deletes every key in the map.
+func (m *Map[K, V]) Clear() {
+	m.mu.Lock()
+	m.v.Store(map[K]*entry[V](nil))
+	m.dirty = nil
+	m.misses = 0
+	m.mu.Unlock()
+}
+
+// promoted forces the dirty map to be promoted to the read map and returns
+// the resulting read map, so callers can iterate over a stable snapshot of
+// live entries.
+func (m *Map[K, V]) promoted() map[K]*entry[V] {
+	m.mu.Lock()
+	// Force miss to promote.
+	m.misses = len(m.dirty) - 1
+	m.miss()
+	mv, _ := m.v.Load().(map[K]*entry[V])
+	m.mu.Unlock()
+	return mv
+}
+
 // miss updates the miss counter and possibly promotes the dirty map. The
 // caller must hold m.mu.
-func (m *Map) miss() {
+func (m *Map[K, V]) miss() {
 	m.misses++
 	if m.misses < len(m.dirty) {
 		return
 	}
 	mv := m.dirty
 	m.v.Store(mv)
-	m.dirty = make(map[string]*entry, len(mv))
+	m.dirty = make(map[K]*entry[V], len(mv))
 	for k, v := range mv {
-		if atomic.LoadPointer(&v.p) != nil {
+		if v.p.Load() != nil {
 			m.dirty[k] = v
 		}
 	}
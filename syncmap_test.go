@@ -12,7 +12,7 @@ import (
 )
 
 func TestMap(t *testing.T) {
-	var m syncmap.Map
+	var m syncmap.Map[string, any]
 	keys := strings.Fields("a b c d e f g h i j k l m n o p q r s t u v w x y z")
 	ch := make(chan error, len(keys))
 	start := make(chan bool)
@@ -64,7 +64,7 @@ func TestMap(t *testing.T) {
 }
 
 func TestStoreOnly(t *testing.T) {
-	var m syncmap.Map
+	var m syncmap.Map[string, any]
 	var wg sync.WaitGroup
 	ch := make(chan bool)
 	n := runtime.GOMAXPROCS(0)
@@ -86,7 +86,7 @@ func TestStoreOnly(t *testing.T) {
 }
 
 func TestMapKey(t *testing.T) {
-	var m syncmap.Map
+	var m syncmap.Map[string, any]
 	var wg sync.WaitGroup
 	n := runtime.GOMAXPROCS(0)
 	wg.Add(n)
@@ -111,7 +111,7 @@ func TestMapKey(t *testing.T) {
 }
 
 func TestLoadOrStore(t *testing.T) {
-	var m syncmap.Map
+	var m syncmap.Map[string, any]
 	n := runtime.GOMAXPROCS(0)
 	ch := make(chan bool)
 	errs := make(chan error, n)
@@ -151,12 +151,12 @@ func TestLoadOrStore(t *testing.T) {
 func TestDelete(t *testing.T) {
 	// Test that deleting with a nil m.v doesn't panic.
 	t.Run("empty", func(t *testing.T) {
-		var m syncmap.Map
+		var m syncmap.Map[string, any]
 		m.Delete("")
 	})
 	// Test that storing and deleting a value removes it.
 	t.Run("semantic", func(t *testing.T) {
-		var m syncmap.Map
+		var m syncmap.Map[string, any]
 		m.Store("k", 0)
 		m.Delete("k")
 		v, ok := m.Load("k")
@@ -169,7 +169,7 @@ func TestDelete(t *testing.T) {
 	})
 	// Test that concurrent reads to a value eventually see a delete.
 	t.Run("concurrent", func(t *testing.T) {
-		var m syncmap.Map
+		var m syncmap.Map[string, any]
 		start := make(chan bool)
 		read := make(chan bool)
 		errs := make(chan error, 1)
@@ -204,7 +204,7 @@ func TestDelete(t *testing.T) {
 func TestLoadAndDelete(t *testing.T) {
 	// Test that deleting with a nil m.v returns nil.
 	t.Run("empty", func(t *testing.T) {
-		var m syncmap.Map
+		var m syncmap.Map[string, any]
 		r, ok := m.LoadAndDelete("")
 		if ok {
 			t.Errorf("empty map loaded %#v", r)
@@ -215,7 +215,7 @@ func TestLoadAndDelete(t *testing.T) {
 	})
 	// Test that storing and deleting a value removes it.
 	t.Run("semantic", func(t *testing.T) {
-		var m syncmap.Map
+		var m syncmap.Map[string, any]
 		m.Store("k", 0)
 		v, ok := m.LoadAndDelete("k")
 		if !ok {
@@ -234,7 +234,7 @@ func TestLoadAndDelete(t *testing.T) {
 	})
 	// Test that concurrent reads to a value eventually see a delete.
 	t.Run("concurrent", func(t *testing.T) {
-		var m syncmap.Map
+		var m syncmap.Map[string, any]
 		start := make(chan bool)
 		read := make(chan bool)
 		errs := make(chan error, 1)
@@ -272,7 +272,7 @@ func TestLoadAndDelete(t *testing.T) {
 	})
 	// Test that concurrent stores and deletes see each other.
 	t.Run("write", func(t *testing.T) {
-		var m syncmap.Map
+		var m syncmap.Map[string, any]
 		m.Store("k", 0)
 		start := make(chan bool)
 		errs := make(chan error, 1)
@@ -315,7 +315,7 @@ func TestLoadAndDelete(t *testing.T) {
 
 func TestRange(t *testing.T) {
 	t.Run("all", func(t *testing.T) {
-		var m syncmap.Map
+		var m syncmap.Map[string, any]
 		keys := strings.Fields("a b c d e f g h i j k l m n o p q r s t u v w x y z")
 		for _, k := range keys {
 			m.Store(k, k)
@@ -338,7 +338,7 @@ func TestRange(t *testing.T) {
 		}
 	})
 	t.Run("happens-before", func(t *testing.T) {
-		var m syncmap.Map
+		var m syncmap.Map[string, any]
 		for i := 1; i <= 100; i++ {
 			m.Store(string(rune(i)), i)
 			sum := 0
@@ -352,7 +352,7 @@ func TestRange(t *testing.T) {
 		}
 	})
 	t.Run("stop", func(t *testing.T) {
-		var m syncmap.Map
+		var m syncmap.Map[string, any]
 		keys := strings.Fields("a b c d e f g h i j k l m n o p q r s t u v w x y z")
 		for _, k := range keys {
 			m.Store(k, k)
@@ -367,7 +367,7 @@ func TestRange(t *testing.T) {
 		}
 	})
 	t.Run("loop-use", func(t *testing.T) {
-		var m syncmap.Map
+		var m syncmap.Map[string, any]
 		keys := strings.Fields("a b c d e f g h i j k l m n o p q r s t u v w x y z")
 		for _, k := range keys {
 			m.Store(k, k)
@@ -392,3 +392,205 @@ func TestRange(t *testing.T) {
 		})
 	})
 }
+
+func TestSwap(t *testing.T) {
+	t.Run("missing", func(t *testing.T) {
+		var m syncmap.Map[string, any]
+		v, loaded := m.Swap("k", 1)
+		if loaded {
+			t.Errorf("swap on missing key reported loaded, value %v", v)
+		}
+		r, ok := m.Load("k")
+		if !ok || r != 1 {
+			t.Errorf("swap did not store value: got %v, %t", r, ok)
+		}
+	})
+	t.Run("semantic", func(t *testing.T) {
+		var m syncmap.Map[string, any]
+		m.Store("k", 0)
+		v, loaded := m.Swap("k", 1)
+		if !loaded || v != 0 {
+			t.Errorf("swap returned wrong previous value: want 0/true, got %v/%t", v, loaded)
+		}
+		r, _ := m.Load("k")
+		if r != 1 {
+			t.Errorf("swap did not store new value: got %v", r)
+		}
+	})
+	// Test that many goroutines swapping the same key concurrently don't
+	// corrupt the entry.
+	t.Run("write", func(t *testing.T) {
+		var m syncmap.Map[string, any]
+		var wg sync.WaitGroup
+		ch := make(chan bool)
+		n := runtime.GOMAXPROCS(0)
+		wg.Add(n)
+		f := func(v int) {
+			<-ch
+			for i := 0; i < 1e5; i++ {
+				m.Swap("k", v)
+			}
+			wg.Done()
+		}
+		for i := 0; i < n; i++ {
+			go f(i)
+		}
+		close(ch)
+		wg.Wait()
+		// No correct result, except that the race detector shouldn't complain.
+	})
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	t.Run("semantic", func(t *testing.T) {
+		var m syncmap.Map[string, any]
+		m.Store("k", 0)
+		if !m.CompareAndSwap("k", 0, 1) {
+			t.Errorf("CompareAndSwap failed on matching old value")
+		}
+		v, _ := m.Load("k")
+		if v != 1 {
+			t.Errorf("CompareAndSwap stored wrong value: want 1, got %v", v)
+		}
+		if m.CompareAndSwap("k", 0, 2) {
+			t.Errorf("CompareAndSwap succeeded on stale old value")
+		}
+	})
+	t.Run("missing", func(t *testing.T) {
+		var m syncmap.Map[string, any]
+		if m.CompareAndSwap("k", nil, 1) {
+			t.Errorf("CompareAndSwap succeeded on missing key")
+		}
+	})
+	// Test that CompareAndSwap panics rather than silently misbehaving when
+	// V's dynamic type is not comparable, matching sync.Map's documented
+	// behavior.
+	t.Run("uncomparable", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("CompareAndSwap on uncomparable value did not panic")
+			}
+		}()
+		var m syncmap.Map[string, any]
+		m.Store("k", []int{1})
+		m.CompareAndSwap("k", []int{1}, []int{2})
+	})
+	// Test that many goroutines racing CompareAndSwap on the same key make
+	// forward progress and never apply a stale old value.
+	t.Run("write", func(t *testing.T) {
+		var m syncmap.Map[string, any]
+		m.Store("k", 0)
+		start := make(chan bool)
+		errs := make(chan error, 1)
+		n := runtime.GOMAXPROCS(0)
+		f := func() {
+			defer func() { errs <- nil }()
+			<-start
+			for i := 0; i < 1e7; i++ {
+				v, ok := m.Load("k")
+				if !ok {
+					continue
+				}
+				x := v.(int)
+				if x >= 1e3 {
+					return
+				}
+				m.CompareAndSwap("k", x, x+1)
+			}
+			errs <- fmt.Errorf("too many iterations")
+		}
+		for i := 0; i < n; i++ {
+			go f()
+		}
+		close(start)
+		for i := 0; i < n; i++ {
+			if err := <-errs; err != nil {
+				t.Error(err)
+				i-- // don't count errors, only nils
+			}
+		}
+		v, _ := m.Load("k")
+		if v != 1000 {
+			t.Errorf("wrong final value: want 1000, got %v", v)
+		}
+	})
+}
+
+func TestCompareAndDelete(t *testing.T) {
+	t.Run("semantic", func(t *testing.T) {
+		var m syncmap.Map[string, any]
+		m.Store("k", 0)
+		if m.CompareAndDelete("k", 1) {
+			t.Errorf("CompareAndDelete succeeded on stale old value")
+		}
+		if !m.CompareAndDelete("k", 0) {
+			t.Errorf("CompareAndDelete failed on matching old value")
+		}
+		if _, ok := m.Load("k"); ok {
+			t.Errorf("key survived CompareAndDelete")
+		}
+	})
+	t.Run("missing", func(t *testing.T) {
+		var m syncmap.Map[string, any]
+		if m.CompareAndDelete("k", nil) {
+			t.Errorf("CompareAndDelete succeeded on missing key")
+		}
+	})
+	// Test that CompareAndDelete panics rather than silently misbehaving when
+	// V's dynamic type is not comparable, matching sync.Map's documented
+	// behavior.
+	t.Run("uncomparable", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("CompareAndDelete on uncomparable value did not panic")
+			}
+		}()
+		var m syncmap.Map[string, any]
+		m.Store("k", []int{1})
+		m.CompareAndDelete("k", []int{1})
+	})
+	// Test that concurrent stores and CompareAndDelete calls see each other.
+	t.Run("write", func(t *testing.T) {
+		var m syncmap.Map[string, any]
+		m.Store("k", 0)
+		start := make(chan bool)
+		errs := make(chan error, 1)
+		n := runtime.GOMAXPROCS(0)
+		f := func() {
+			defer func() { errs <- nil }()
+			seen := -1
+			<-start
+			for i := 0; i < 1e7; i++ {
+				v, ok := m.Load("k")
+				if !ok {
+					runtime.Gosched()
+					continue
+				}
+				x := v.(int)
+				if x == seen {
+					runtime.Gosched()
+					continue
+				}
+				if !m.CompareAndDelete("k", x) {
+					continue
+				}
+				seen = x
+				m.Store("k", x+1)
+				if x >= 1e2 {
+					return
+				}
+			}
+			errs <- fmt.Errorf("too many iterations")
+		}
+		for i := 0; i < n; i++ {
+			go f()
+		}
+		close(start)
+		for i := 0; i < n; i++ {
+			if err := <-errs; err != nil {
+				t.Error(err)
+				i-- // don't count errors, only nils
+			}
+		}
+	})
+}
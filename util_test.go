@@ -0,0 +1,73 @@
+package syncmap_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zephyrtronium/syncmap"
+)
+
+func TestLen(t *testing.T) {
+	var m syncmap.Map[string, int]
+	if n := m.Len(); n != 0 {
+		t.Errorf("empty map has nonzero length %d", n)
+	}
+	keys := strings.Fields("a b c d e f g h i j k l m n o p q r s t u v w x y z")
+	for i, k := range keys {
+		m.Store(k, i)
+	}
+	if n := m.Len(); n != len(keys) {
+		t.Errorf("wrong length: want %d, got %d", len(keys), n)
+	}
+	m.Delete(keys[0])
+	// Len is approximate: a deleted key can still be counted for a couple of
+	// promotion cycles after it is removed, so only check that it doesn't
+	// grow past the original count.
+	if n := m.Len(); n > len(keys) {
+		t.Errorf("length grew after delete: want at most %d, got %d", len(keys), n)
+	}
+}
+
+func TestClone(t *testing.T) {
+	var m syncmap.Map[string, int]
+	keys := strings.Fields("a b c d e f g h i j k l m n o p q r s t u v w x y z")
+	for i, k := range keys {
+		m.Store(k, i)
+	}
+	c := m.Clone()
+	if len(c) != len(keys) {
+		t.Errorf("wrong clone length: want %d, got %d", len(keys), len(c))
+	}
+	for i, k := range keys {
+		if c[k] != i {
+			t.Errorf("wrong cloned value for key %v: want %d, got %d", k, i, c[k])
+		}
+	}
+	// Mutating the map after cloning must not affect the clone.
+	m.Store(keys[0], -1)
+	if c[keys[0]] != 0 {
+		t.Errorf("clone observed mutation of source map")
+	}
+}
+
+func TestClear(t *testing.T) {
+	var m syncmap.Map[string, int]
+	keys := strings.Fields("a b c d e f g h i j k l m n o p q r s t u v w x y z")
+	for i, k := range keys {
+		m.Store(k, i)
+	}
+	m.Clear()
+	if n := m.Len(); n != 0 {
+		t.Errorf("map has nonzero length %d after Clear", n)
+	}
+	for _, k := range keys {
+		if _, ok := m.Load(k); ok {
+			t.Errorf("key %v survived Clear", k)
+		}
+	}
+	// The map must still be usable after being cleared.
+	m.Store("k", 1)
+	if v, ok := m.Load("k"); !ok || v != 1 {
+		t.Errorf("store after Clear failed: got %v, %t", v, ok)
+	}
+}